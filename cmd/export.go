@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
 // exportCmd represents the export command
@@ -123,10 +125,57 @@ var exportCmd = &cobra.Command{
 			}
 		}
 
+		if f := viper.GetString("path-format"); f == "json" || f == "yaml" {
+			var infos []*path.PathInfo
+			path.PathsInfo(e, path.PathInfo{}, &infos)
+
+			switch viper.GetString("path-only-nodes") {
+			case "config":
+				infos = filterConfig(infos, true)
+			case "state":
+				infos = filterConfig(infos, false)
+			}
+
+			if err := printPathInfo(f, infos); err != nil {
+				log.Fatal(err)
+			}
+		}
+
 		return nil
 	},
 }
 
+// filterConfig returns the entries of infos whose Config state matches want,
+// mirroring the text renderer's --only-nodes filtering for the json/yaml formats.
+func filterConfig(infos []*path.PathInfo, want bool) []*path.PathInfo {
+	var out []*path.PathInfo
+	for _, i := range infos {
+		if i.Config == want {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// printPathInfo marshals infos to stdout in the given format ("json" or "yaml").
+func printPathInfo(format string, infos []*path.PathInfo) error {
+	if format == "yaml" {
+		b, err := yaml.Marshal(infos)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		return nil
+	}
+
+	b, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(exportCmd)
 
@@ -137,7 +186,7 @@ func init() {
 	viper.BindPFlag("module", exportCmd.Flags().Lookup("module"))
 	exportCmd.MarkFlagRequired("module")
 
-	exportCmd.Flags().StringP("format", "f", "text", "paths output format. One of [text, html]")
+	exportCmd.Flags().StringP("format", "f", "text", "paths output format. One of [text, html, json, yaml]")
 	viper.BindPFlag("path-format", exportCmd.Flags().Lookup("format"))
 
 	exportCmd.Flags().StringP("style", "s", "xpath", "style of the path. One of [xpath, restconf]")
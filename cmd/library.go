@@ -0,0 +1,110 @@
+// Copyright © 2020 Roman Dodin <dodin.roman@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hellt/yangpath/pkg/library"
+	path "github.com/hellt/yangpath/pkg/path"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// libraryCmd represents the library command
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "emit an ietf-yang-library module inventory for the given YANG modules",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dirs := viper.GetStringSlice("library-yang-dir")
+
+		if err := path.AddYANGDirs(dirs); err != nil {
+			log.Fatal(err)
+		}
+
+		files, err := yangFiles(dirs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(files) == 0 {
+			log.Fatal("no YANG modules found under the directories specified with --yang-dir.")
+		}
+
+		mods, err := library.Gather(files, viper.GetStringSlice("library-module"), viper.GetString("library-schema-root-url"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var out interface{}
+		switch viper.GetString("library-format") {
+		case "yang-library-1.1":
+			out = library.ToYangLibrary(mods)
+		default:
+			out = library.ToModulesState(mods)
+		}
+
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(b))
+
+		return nil
+	},
+}
+
+// yangFiles recursively collects every ".yang" file found under dirs.
+func yangFiles(dirs []string) ([]string, error) {
+	var files []string
+	for _, d := range dirs {
+		err := filepath.Walk(d, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(info.Name(), ".yang") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func init() {
+	rootCmd.AddCommand(libraryCmd)
+
+	libraryCmd.Flags().StringSliceP("yang-dir", "y", []string{"./"}, "directory(-ies) with YANG modules, walked recursively. Values can be provided multiple times (-y dir1 -y dir2)")
+	viper.BindPFlag("library-yang-dir", libraryCmd.Flags().Lookup("yang-dir"))
+
+	libraryCmd.Flags().StringSliceP("module", "m", []string{}, "name(s) of the YANG module(s) to report as conformance-type 'implement'; everything else found is reported as 'import'")
+	viper.BindPFlag("library-module", libraryCmd.Flags().Lookup("module"))
+
+	libraryCmd.Flags().StringP("format", "f", "yang-library-1.0", "library document format. One of [yang-library-1.0, yang-library-1.1]")
+	viper.BindPFlag("library-format", libraryCmd.Flags().Lookup("format"))
+
+	libraryCmd.Flags().StringP("schema-root-url", "", "", "base URL to prefix onto each module's file name to populate its schema location")
+	viper.BindPFlag("library-schema-root-url", libraryCmd.Flags().Lookup("schema-root-url"))
+}
@@ -0,0 +1,363 @@
+// Copyright © 2020 Roman Dodin <dodin.roman@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package library builds an ietf-yang-library inventory (RFC 7895 and
+// RFC 8525) out of the YANG modules found on disk.
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Submodule describes a submodule belonging to an implemented or imported module.
+type Submodule struct {
+	Name     string `json:"name"`
+	Revision string `json:"revision,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+}
+
+// Deviation7895 identifies a module that deviates another module, as used by
+// the RFC 7895 "deviation" list.
+type Deviation7895 struct {
+	Name     string `json:"name"`
+	Revision string `json:"revision,omitempty"`
+}
+
+// Module7895 is a single ietf-yang-library:modules-state/module entry (RFC 7895).
+type Module7895 struct {
+	Name            string          `json:"name"`
+	Revision        string          `json:"revision,omitempty"`
+	Schema          string          `json:"schema,omitempty"`
+	Namespace       string          `json:"namespace"`
+	Feature         []string        `json:"feature,omitempty"`
+	Deviation       []Deviation7895 `json:"deviation,omitempty"`
+	ConformanceType string          `json:"conformance-type"`
+	Submodule       []Submodule     `json:"submodule,omitempty"`
+}
+
+// ModulesState is the ietf-yang-library:modules-state container (RFC 7895).
+type ModulesState struct {
+	ModuleSetID string        `json:"module-set-id"`
+	Module      []*Module7895 `json:"module"`
+}
+
+// Module8525 is a module entry within an RFC 8525 module-set.
+type Module8525 struct {
+	Name      string      `json:"name"`
+	Revision  string      `json:"revision,omitempty"`
+	Namespace string      `json:"namespace"`
+	Location  []string    `json:"location,omitempty"`
+	Submodule []Submodule `json:"submodule,omitempty"`
+	Feature   []string    `json:"feature,omitempty"`
+	Deviation []string    `json:"deviation,omitempty"`
+}
+
+// ModuleSet is a single ietf-yang-library:yang-library/module-set entry (RFC 8525).
+type ModuleSet struct {
+	Name   string        `json:"name"`
+	Module []*Module8525 `json:"module"`
+}
+
+// YangLibrary is the ietf-yang-library:yang-library container (RFC 8525).
+//
+// Only the module-set list and content-id are populated: the schema and
+// datastore lists bind a module-set to a running datastore, which is a
+// deployment-time concern outside the scope of a build-time inventory.
+type YangLibrary struct {
+	ModuleSet []*ModuleSet `json:"module-set"`
+	ContentID string       `json:"content-id"`
+}
+
+// module is the format-agnostic view of a parsed YANG module collected by Gather.
+type module struct {
+	name      string
+	revision  string
+	namespace string
+	schema    string
+	implement bool
+	feature   []string
+	deviation []Deviation7895
+	submodule []Submodule
+}
+
+// Gather parses every YANG module found under files, matching each parsed
+// module against the source file it came from, and classifies modules named
+// in implement as "implement" (everything else is "import").
+//
+// files must already be resolvable via the module search path (see
+// path.AddYANGDirs); schemaRootURL, when non-empty, is joined with the
+// module's file name to populate the schema location.
+func Gather(files []string, implement []string, schemaRootURL string) ([]*module, error) {
+	want := make(map[string]bool, len(implement))
+	for _, n := range implement {
+		want[n] = true
+	}
+
+	ms := yang.NewModules()
+
+	// goyang only associates a parsed module/submodule with its source file
+	// for the duration of the Read call that loaded it, so we record which
+	// name(s) appeared in ms.Modules/ms.SubModules after each Read to learn
+	// the file it came from.
+	fileOf := make(map[string]string)
+	for _, f := range files {
+		before := snapshotNames(ms)
+		if err := ms.Read(f); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		for _, n := range diffNames(ms, before) {
+			fileOf[n] = f
+		}
+	}
+
+	for _, err := range ms.Process() {
+		return nil, err
+	}
+
+	deviators := deviatorsByTarget(ms)
+
+	var mods []*module
+	for name, m := range ms.Modules {
+		if strings.Contains(name, "@") {
+			// a module keyed with a revision suffix duplicates the bare
+			// module entry (see openconfig/goyang#137); skip it.
+			continue
+		}
+		mods = append(mods, buildModule(m, ms, want[name], fileOf, schemaRootURL, deviators[name]))
+	}
+
+	sort.Slice(mods, func(i, j int) bool { return mods[i].name < mods[j].name })
+
+	return mods, nil
+}
+
+func buildModule(m *yang.Module, ms *yang.Modules, implement bool, fileOf map[string]string, schemaRootURL string, deviation []Deviation7895) *module {
+	out := &module{
+		name:      m.Name,
+		revision:  latestRevision(m.Revision),
+		implement: implement,
+		schema:    schemaLocation(fileOf[m.Name], schemaRootURL),
+		deviation: deviation,
+	}
+	if m.Namespace != nil {
+		out.namespace = m.Namespace.Name
+	}
+
+	for _, f := range m.Feature {
+		out.feature = append(out.feature, f.Name)
+	}
+
+	for _, inc := range m.Include {
+		sm, ok := ms.SubModules[inc.Name]
+		if !ok {
+			continue
+		}
+		out.submodule = append(out.submodule, Submodule{
+			Name:     sm.Name,
+			Revision: latestRevision(sm.Revision),
+			Schema:   schemaLocation(fileOf[sm.Name], schemaRootURL),
+		})
+	}
+	sort.Slice(out.submodule, func(i, j int) bool { return out.submodule[i].Name < out.submodule[j].Name })
+
+	return out
+}
+
+// deviatorsByTarget indexes, once over ms, which modules contain a
+// "deviation" statement targeting which other module, keyed by the target
+// module's name.
+func deviatorsByTarget(ms *yang.Modules) map[string][]Deviation7895 {
+	byTarget := make(map[string][]Deviation7895)
+	for name, other := range ms.Modules {
+		if strings.Contains(name, "@") || len(other.Deviation) == 0 {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, d := range other.Deviation {
+			target := deviationTargetModule(d.Name, other)
+			if target == "" || seen[target] {
+				continue
+			}
+			seen[target] = true
+			byTarget[target] = append(byTarget[target], Deviation7895{Name: other.Name, Revision: latestRevision(other.Revision)})
+		}
+	}
+	for target := range byTarget {
+		sort.Slice(byTarget[target], func(i, j int) bool { return byTarget[target][i].Name < byTarget[target][j].Name })
+	}
+	return byTarget
+}
+
+// deviationTargetModule resolves the module name targeted by a deviation
+// statement's argument, e.g. "/prefix:container/prefix:leaf", using the
+// prefix-to-module mapping declared by the deviating module.
+func deviationTargetModule(target string, deviating *yang.Module) string {
+	parts := strings.SplitN(strings.TrimPrefix(target, "/"), "/", 2)
+	if len(parts) == 0 {
+		return ""
+	}
+	prefix := parts[0]
+	if i := strings.Index(prefix, ":"); i >= 0 {
+		prefix = prefix[:i]
+	} else {
+		return ""
+	}
+	if deviating.Prefix != nil && deviating.Prefix.Name == prefix {
+		return deviating.Name
+	}
+	for _, imp := range deviating.Import {
+		if imp.Prefix != nil && imp.Prefix.Name == prefix {
+			return imp.Name
+		}
+	}
+	return ""
+}
+
+func latestRevision(revs []*yang.Revision) string {
+	var latest string
+	for _, r := range revs {
+		if r.Name > latest {
+			latest = r.Name
+		}
+	}
+	return latest
+}
+
+// schemaLocation builds the schema leaf for file, joined onto schemaRootURL.
+// Per the --schema-root-url flag's contract, the leaf is only populated when
+// that flag is set; otherwise modules are reported without a schema location.
+func schemaLocation(file, schemaRootURL string) string {
+	if file == "" || schemaRootURL == "" {
+		return ""
+	}
+	name := file
+	if i := strings.LastIndex(file, "/"); i >= 0 {
+		name = file[i+1:]
+	}
+	return strings.TrimSuffix(schemaRootURL, "/") + "/" + name
+}
+
+func snapshotNames(ms *yang.Modules) map[string]bool {
+	names := make(map[string]bool, len(ms.Modules)+len(ms.SubModules))
+	for n := range ms.Modules {
+		names[n] = true
+	}
+	for n := range ms.SubModules {
+		names[n] = true
+	}
+	return names
+}
+
+func diffNames(ms *yang.Modules, before map[string]bool) []string {
+	var added []string
+	for n := range ms.Modules {
+		if !before[n] {
+			added = append(added, n)
+		}
+	}
+	for n := range ms.SubModules {
+		if !before[n] {
+			added = append(added, n)
+		}
+	}
+	return added
+}
+
+// ToModulesState renders mods as an ietf-yang-library:modules-state
+// container (RFC 7895).
+func ToModulesState(mods []*module) *ModulesState {
+	ms := &ModulesState{ModuleSetID: contentID(mods)}
+	for _, m := range mods {
+		conf := "import"
+		if m.implement {
+			conf = "implement"
+		}
+		ms.Module = append(ms.Module, &Module7895{
+			Name:            m.name,
+			Revision:        m.revision,
+			Schema:          m.schema,
+			Namespace:       m.namespace,
+			Feature:         m.feature,
+			Deviation:       m.deviation,
+			ConformanceType: conf,
+			Submodule:       m.submodule,
+		})
+	}
+	return ms
+}
+
+// ToYangLibrary renders mods as a single-module-set
+// ietf-yang-library:yang-library container (RFC 8525).
+func ToYangLibrary(mods []*module) *YangLibrary {
+	set := &ModuleSet{Name: "common"}
+	for _, m := range mods {
+		var dev []string
+		for _, d := range m.deviation {
+			dev = append(dev, d.Name)
+		}
+		set.Module = append(set.Module, &Module8525{
+			Name:      m.name,
+			Revision:  m.revision,
+			Namespace: m.namespace,
+			Location:  locationOf(m.schema),
+			Submodule: m.submodule,
+			Feature:   m.feature,
+			Deviation: dev,
+		})
+	}
+	return &YangLibrary{
+		ModuleSet: []*ModuleSet{set},
+		ContentID: contentID(mods),
+	}
+}
+
+func locationOf(schema string) []string {
+	if schema == "" {
+		return nil
+	}
+	return []string{schema}
+}
+
+// contentID computes a stable identifier over the sorted
+// {name,revision,features,deviations} tuples of mods, as required of a
+// yang-library content-id (RFC 8525 section 3).
+func contentID(mods []*module) string {
+	tuples := make([]string, 0, len(mods))
+	for _, m := range mods {
+		features := append([]string(nil), m.feature...)
+		sort.Strings(features)
+
+		deviations := make([]string, 0, len(m.deviation))
+		for _, d := range m.deviation {
+			deviations = append(deviations, d.Name+"@"+d.Revision)
+		}
+		sort.Strings(deviations)
+
+		tuples = append(tuples, strings.Join([]string{
+			m.name, m.revision,
+			strings.Join(features, ","),
+			strings.Join(deviations, ","),
+		}, "|"))
+	}
+	sort.Strings(tuples)
+
+	h := sha256.Sum256([]byte(strings.Join(tuples, "\n")))
+	return hex.EncodeToString(h[:])
+}
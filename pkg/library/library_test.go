@@ -0,0 +1,184 @@
+// Copyright © 2020 Roman Dodin <dodin.roman@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const moduleFoo = `
+module foo {
+  namespace "urn:foo";
+  prefix f;
+
+  revision 2020-01-01 {
+    description "initial";
+  }
+  revision 2021-06-15 {
+    description "second";
+  }
+
+  include foo-types;
+
+  feature extra;
+
+  container top {
+    leaf name { type string; }
+  }
+}
+`
+
+const submoduleFooTypes = `
+submodule foo-types {
+  belongs-to foo { prefix f; }
+
+  revision 2021-06-15 {
+    description "second";
+  }
+}
+`
+
+const moduleBar = `
+module bar {
+  namespace "urn:bar";
+  prefix b;
+}
+`
+
+const moduleFooDeviations = `
+module foo-deviations {
+  namespace "urn:foo-deviations";
+  prefix fd;
+
+  import foo { prefix f; }
+
+  deviation /f:top/f:name {
+    deviate not-supported;
+  }
+}
+`
+
+func writeYANGFiles(t *testing.T, dir string, files map[string]string) []string {
+	t.Helper()
+	var paths []string
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", p, err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func TestGather(t *testing.T) {
+	dir := t.TempDir()
+	files := writeYANGFiles(t, dir, map[string]string{
+		"foo.yang":            moduleFoo,
+		"foo-types.yang":      submoduleFooTypes,
+		"bar.yang":            moduleBar,
+		"foo-deviations.yang": moduleFooDeviations,
+	})
+
+	mods, err := Gather(files, []string{"foo"}, "")
+	if err != nil {
+		t.Fatalf("Gather() error: %v", err)
+	}
+
+	byName := make(map[string]*module)
+	for _, m := range mods {
+		byName[m.name] = m
+	}
+
+	foo, ok := byName["foo"]
+	if !ok {
+		t.Fatalf("module foo not found in %v", names(mods))
+	}
+	if !foo.implement {
+		t.Error("foo.implement = false, want true (named via implement arg)")
+	}
+	if foo.revision != "2021-06-15" {
+		t.Errorf("foo.revision = %q, want %q (latest of the two declared)", foo.revision, "2021-06-15")
+	}
+	if foo.namespace != "urn:foo" {
+		t.Errorf("foo.namespace = %q, want %q", foo.namespace, "urn:foo")
+	}
+	if len(foo.feature) != 1 || foo.feature[0] != "extra" {
+		t.Errorf("foo.feature = %v, want [extra]", foo.feature)
+	}
+	if len(foo.submodule) != 1 || foo.submodule[0].Name != "foo-types" {
+		t.Errorf("foo.submodule = %v, want [foo-types]", foo.submodule)
+	}
+	if len(foo.deviation) != 1 || foo.deviation[0].Name != "foo-deviations" {
+		t.Errorf("foo.deviation = %v, want [foo-deviations]", foo.deviation)
+	}
+
+	bar, ok := byName["bar"]
+	if !ok {
+		t.Fatalf("module bar not found in %v", names(mods))
+	}
+	if bar.implement {
+		t.Error("bar.implement = true, want false (not named via implement arg)")
+	}
+}
+
+func TestSchemaLocation(t *testing.T) {
+	cases := []struct {
+		file, root, want string
+	}{
+		{"", "", ""},
+		{"/yang/foo.yang", "", ""},
+		{"/yang/foo.yang", "https://example.com/schemas", "https://example.com/schemas/foo.yang"},
+		{"/yang/foo.yang", "https://example.com/schemas/", "https://example.com/schemas/foo.yang"},
+	}
+	for _, c := range cases {
+		if got := schemaLocation(c.file, c.root); got != c.want {
+			t.Errorf("schemaLocation(%q, %q) = %q, want %q", c.file, c.root, got, c.want)
+		}
+	}
+}
+
+func TestContentIDStableAndOrderIndependent(t *testing.T) {
+	a := []*module{
+		{name: "foo", revision: "2020-01-01", feature: []string{"b", "a"}},
+		{name: "bar", revision: "2020-02-02"},
+	}
+	b := []*module{
+		{name: "bar", revision: "2020-02-02"},
+		{name: "foo", revision: "2020-01-01", feature: []string{"a", "b"}},
+	}
+
+	if contentID(a) != contentID(b) {
+		t.Error("contentID should not depend on module or feature order")
+	}
+
+	c := []*module{
+		{name: "foo", revision: "2020-01-02"},
+		{name: "bar", revision: "2020-02-02"},
+	}
+	if contentID(a) == contentID(c) {
+		t.Error("contentID should change when a module's revision changes")
+	}
+}
+
+func names(mods []*module) []string {
+	var out []string
+	for _, m := range mods {
+		out = append(out, m.name)
+	}
+	return out
+}
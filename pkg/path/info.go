@@ -0,0 +1,220 @@
+// Copyright © 2020 Roman Dodin <dodin.roman@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package path
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// LeafType is the structured representation of a leaf's type, resolving
+// leafref targets, identityref bases and union members instead of
+// collapsing them into a display string.
+type LeafType struct {
+	Name            string      `json:"name" yaml:"name"`
+	LeafrefTarget   string      `json:"leafref_target,omitempty" yaml:"leafref_target,omitempty"`
+	IdentityBase    string      `json:"identitybase,omitempty" yaml:"identitybase,omitempty"`
+	IdentityDerived []string    `json:"identity_derived,omitempty" yaml:"identity_derived,omitempty"`
+	EnumNames       []string    `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Union           []*LeafType `json:"union,omitempty" yaml:"union,omitempty"`
+}
+
+// GNMIPathElem is a single gNMI path element, carrying the names of its
+// list keys (if any) alongside the element name.
+type GNMIPathElem struct {
+	Name string   `json:"name" yaml:"name"`
+	Key  []string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// PathInfo is the structured, machine-consumable counterpart of Path: every
+// field a downstream tool (schema-registry loaders, doc generators, test
+// fixture builders) would otherwise have to re-derive from the YANG tree
+// itself.
+type PathInfo struct {
+	Module       string         `json:"module" yaml:"module"`
+	Namespace    string         `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	XPath        string         `json:"xpath" yaml:"xpath"`
+	RestConfPath string         `json:"restconf_path" yaml:"restconf_path"`
+	GNMIPath     []GNMIPathElem `json:"gnmi_path" yaml:"gnmi_path"`
+	Kind         string         `json:"kind" yaml:"kind"`
+	Config       bool           `json:"config" yaml:"config"`
+	Keys         []string       `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Type         *LeafType      `json:"type,omitempty" yaml:"type,omitempty"`
+	Description  string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Units        string         `json:"units,omitempty" yaml:"units,omitempty"`
+	Default      string         `json:"default,omitempty" yaml:"default,omitempty"`
+	Mandatory    bool           `json:"mandatory,omitempty" yaml:"mandatory,omitempty"`
+	Status       string         `json:"status,omitempty" yaml:"status,omitempty"`
+	When         []string       `json:"when,omitempty" yaml:"when,omitempty"`
+	Must         []string       `json:"must,omitempty" yaml:"must,omitempty"`
+}
+
+// PathsInfo recursively traverses the entry's e directory Dir till the leaf
+// node, populating p PathInfo along the way, same as Paths, but captures the
+// full node metadata (type, description, units, default, mandatory, status,
+// when/must) instead of a colorized display string, and emits an entry for
+// every container/list/leaf/leaf-list node rather than leaves only.
+func PathsInfo(e *yang.Entry, p PathInfo, ps *[]*PathInfo) {
+	// per-node metadata describes e alone and must never leak to its
+	// children through the recursive copy of p below.
+	p.Description, p.Status, p.Units, p.Default = "", "", "", ""
+	p.When, p.Must, p.Keys, p.Type = nil, nil, nil, nil
+	p.Mandatory = false
+
+	switch e.Node.(type) {
+	case *yang.Module:
+		p.Module = e.Name
+		// config defaults to true unless overridden by an explicit
+		// "config false;" somewhere in the ancestry (RFC 7950 §7.21.1).
+		p.Config = true
+		if e.Node.(*yang.Module).Namespace != nil {
+			p.Namespace = e.Node.(*yang.Module).Namespace.Name
+		}
+	case *yang.Container:
+		p.XPath += fmt.Sprintf("/%s", e.Name)
+		p.RestConfPath += fmt.Sprintf("/%s", e.Name)
+		p.GNMIPath = append(append([]GNMIPathElem{}, p.GNMIPath...), GNMIPathElem{Name: e.Name})
+		p.Kind = "container"
+		if e.Config != yang.TSUnset {
+			p.Config = e.Config == yang.TSTrue
+		}
+		setStatementInfo(&p, e.Node.(*yang.Container).Description, e.Node.(*yang.Container).Status, e.Node.(*yang.Container).When, e.Node.(*yang.Container).Must)
+		*ps = append(*ps, p.clone())
+	case *yang.List:
+		if e.Config != yang.TSUnset {
+			p.Config = e.Config == yang.TSTrue
+		}
+		var keys []string
+		if e.Key != "" {
+			keys = strings.Split(e.Key, " ")
+		}
+		var xKElem, rKElem string
+		for _, k := range keys {
+			xKElem += fmt.Sprintf("[%s=*]", k)
+		}
+		rKElem = strings.Join(keys, ",")
+		p.XPath += fmt.Sprintf("/%s%s", e.Name, xKElem)
+		p.RestConfPath += fmt.Sprintf("/%s=%s", e.Name, rKElem)
+		p.GNMIPath = append(append([]GNMIPathElem{}, p.GNMIPath...), GNMIPathElem{Name: e.Name, Key: keys})
+		p.Kind = "list"
+		p.Keys = keys
+		setStatementInfo(&p, e.Node.(*yang.List).Description, e.Node.(*yang.List).Status, e.Node.(*yang.List).When, e.Node.(*yang.List).Must)
+		*ps = append(*ps, p.clone())
+	case *yang.LeafList:
+		if e.Config != yang.TSUnset {
+			p.Config = e.Config == yang.TSTrue
+		}
+		p.XPath += fmt.Sprintf("/%s", e.Name)
+		p.RestConfPath += fmt.Sprintf("/%s", e.Name)
+		p.GNMIPath = append(append([]GNMIPathElem{}, p.GNMIPath...), GNMIPathElem{Name: e.Name})
+		p.Kind = "leaf-list"
+		ll := e.Node.(*yang.LeafList)
+		p.Type = buildLeafType(e.Type)
+		if ll.Units != nil {
+			p.Units = ll.Units.Name
+		}
+		setStatementInfo(&p, ll.Description, ll.Status, ll.When, ll.Must)
+		*ps = append(*ps, p.clone())
+	case *yang.Leaf:
+		if e.Config != yang.TSUnset {
+			p.Config = e.Config == yang.TSTrue
+		}
+		p.XPath += fmt.Sprintf("/%s", e.Name)
+		p.RestConfPath += fmt.Sprintf("/%s", e.Name)
+		p.GNMIPath = append(append([]GNMIPathElem{}, p.GNMIPath...), GNMIPathElem{Name: e.Name})
+		p.Kind = "leaf"
+		l := e.Node.(*yang.Leaf)
+		p.Type = buildLeafType(e.Type)
+		if l.Units != nil {
+			p.Units = l.Units.Name
+		}
+		if l.Default != nil {
+			p.Default = l.Default.Name
+		}
+		if l.Mandatory != nil {
+			p.Mandatory = l.Mandatory.Name == "true"
+		}
+		setStatementInfo(&p, l.Description, l.Status, l.When, l.Must)
+		*ps = append(*ps, p.clone())
+	}
+
+	ne := make([]string, 0, len(e.Dir))
+	for k := range e.Dir {
+		ne = append(ne, k)
+	}
+	sort.Strings(ne)
+	for _, k := range ne {
+		PathsInfo(e.Dir[k], p, ps)
+	}
+}
+
+// clone returns a pointer to a shallow copy of p: its slices (GNMIPath, Keys,
+// ...) share backing arrays with p. PathsInfo builds those slices afresh for
+// each node instead of appending to them in place, so no entry sees a change
+// made for another.
+func (p PathInfo) clone() *PathInfo {
+	c := p
+	return &c
+}
+
+func setStatementInfo(p *PathInfo, description, status *yang.Value, when *yang.Value, must []*yang.Must) {
+	if description != nil {
+		p.Description = description.Name
+	}
+	if status != nil {
+		p.Status = status.Name
+	}
+	if when != nil {
+		p.When = append(p.When, when.Name)
+	}
+	for _, m := range must {
+		if m.Name != "" {
+			p.Must = append(p.Must, m.Name)
+		}
+	}
+}
+
+func buildLeafType(t *yang.YangType) *LeafType {
+	if t == nil {
+		return nil
+	}
+	lt := &LeafType{Name: t.Name}
+
+	if t.IdentityBase != nil {
+		lt.IdentityBase = t.IdentityBase.Name
+		for _, d := range t.IdentityBase.Values {
+			lt.IdentityDerived = append(lt.IdentityDerived, d.Name)
+		}
+	}
+
+	if t.Kind == yang.Yleafref {
+		lt.LeafrefTarget = t.Path
+	}
+
+	if t.Kind == yang.Yenum && t.Enum != nil {
+		lt.EnumNames = t.Enum.Names()
+	}
+
+	if t.Kind == yang.Yunion {
+		for _, ut := range t.Type {
+			lt.Union = append(lt.Union, buildLeafType(ut))
+		}
+	}
+
+	return lt
+}
@@ -0,0 +1,89 @@
+// Copyright © 2020 Roman Dodin <dodin.roman@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+func TestBuildLeafTypeLeafref(t *testing.T) {
+	lt := buildLeafType(&yang.YangType{
+		Name: "leafref",
+		Kind: yang.Yleafref,
+		Path: "../config/name",
+	})
+	if lt.LeafrefTarget != "../config/name" {
+		t.Errorf("LeafrefTarget = %q, want %q", lt.LeafrefTarget, "../config/name")
+	}
+}
+
+func TestBuildLeafTypeEnum(t *testing.T) {
+	enum := yang.NewEnumType()
+	if err := enum.Set("up", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := enum.Set("down", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	lt := buildLeafType(&yang.YangType{Name: "enumeration", Kind: yang.Yenum, Enum: enum})
+
+	want := []string{"down", "up"} // yang.EnumType.Names() returns them sorted
+	if !reflect.DeepEqual(lt.EnumNames, want) {
+		t.Errorf("EnumNames = %v, want %v", lt.EnumNames, want)
+	}
+}
+
+func TestBuildLeafTypeIdentityref(t *testing.T) {
+	base := &yang.Identity{Name: "iana-if-type"}
+	base.Values = []*yang.Identity{{Name: "ethernetCsmacd"}, {Name: "softwareLoopback"}}
+
+	lt := buildLeafType(&yang.YangType{Name: "identityref", IdentityBase: base})
+
+	if lt.IdentityBase != "iana-if-type" {
+		t.Errorf("IdentityBase = %q, want %q", lt.IdentityBase, "iana-if-type")
+	}
+	want := []string{"ethernetCsmacd", "softwareLoopback"}
+	if !reflect.DeepEqual(lt.IdentityDerived, want) {
+		t.Errorf("IdentityDerived = %v, want %v", lt.IdentityDerived, want)
+	}
+}
+
+func TestBuildLeafTypeUnion(t *testing.T) {
+	lt := buildLeafType(&yang.YangType{
+		Name: "union",
+		Kind: yang.Yunion,
+		Type: []*yang.YangType{
+			{Name: "string"},
+			{Name: "uint32"},
+		},
+	})
+
+	if len(lt.Union) != 2 {
+		t.Fatalf("len(Union) = %d, want 2", len(lt.Union))
+	}
+	if lt.Union[0].Name != "string" || lt.Union[1].Name != "uint32" {
+		t.Errorf("Union = %+v, want [string uint32]", lt.Union)
+	}
+}
+
+func TestBuildLeafTypeNil(t *testing.T) {
+	if lt := buildLeafType(nil); lt != nil {
+		t.Errorf("buildLeafType(nil) = %+v, want nil", lt)
+	}
+}